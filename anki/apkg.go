@@ -0,0 +1,186 @@
+// Package anki builds and reads Anki .apkg decks: a zip archive containing
+// a SQLite collection.anki2 database plus a media manifest.
+package anki
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha1"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+// basicModelID is the built-in "Basic" note type id that every Anki
+// client recognizes without needing a models definition beyond the
+// minimal one we embed in the collection config.
+const basicModelID = 1
+
+// defaultDeckID is used when a card has no topic to group by.
+const defaultDeckID = 1
+
+// Card is one flashcard to embed in an exported deck. The review fields are
+// optional SM-2 state (see scheduler.go's Review); no revlog entry is
+// written for a card with zero Repetitions, whether it has never been
+// reviewed or was reset by a grade below 3.
+type Card struct {
+	Username     string
+	Topic        string
+	Question     string
+	Answer       string
+	Type         string
+	Payload      json.RawMessage
+	EaseFactor   float64
+	IntervalDays int
+	Repetitions  int
+	LastGrade    int
+}
+
+// GUID derives a stable Anki note GUID from the owning user and the
+// question text, so re-exporting the same card always produces the same
+// note instead of a duplicate.
+func GUID(username, question string) string {
+	sum := sha1.Sum([]byte(username + question))
+	return fmt.Sprintf("%x", sum)[:10]
+}
+
+// BuildAPKG packages cards into an Anki-compatible .apkg file, grouping
+// cards into separate decks by topic.
+func BuildAPKG(cards []Card) ([]byte, error) {
+	tmpFile, err := os.CreateTemp("", "collection-*.anki2")
+	if err != nil {
+		return nil, fmt.Errorf("create temp db: %w", err)
+	}
+	dbPath := tmpFile.Name()
+	tmpFile.Close()
+	defer os.Remove(dbPath)
+
+	db, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		return nil, fmt.Errorf("open sqlite: %w", err)
+	}
+	defer db.Close()
+
+	if err := createSchema(db); err != nil {
+		return nil, fmt.Errorf("create schema: %w", err)
+	}
+
+	decks := deckIDsByTopic(cards)
+	if err := writeCollectionRow(db, decks); err != nil {
+		return nil, fmt.Errorf("write col row: %w", err)
+	}
+
+	now := time.Now()
+	for i, card := range cards {
+		deckID := decks[card.Topic]
+		noteID := now.UnixMilli() + int64(i)
+		guid := GUID(card.Username, card.Question)
+		flds := card.Question + "\x1f" + card.Answer
+		csum := checksum(card.Question)
+
+		if _, err := db.Exec(
+			`INSERT INTO notes (id, guid, mid, mod, usn, tags, flds, sfld, csum, flags, data)
+			 VALUES (?, ?, ?, ?, -1, '', ?, ?, ?, 0, '')`,
+			noteID, guid, basicModelID, now.Unix(), flds, card.Question, csum,
+		); err != nil {
+			return nil, fmt.Errorf("insert note %d: %w", i, err)
+		}
+
+		cardID := noteID + 1
+		if _, err := db.Exec(
+			`INSERT INTO cards (id, nid, did, ord, mod, usn, type, queue, due, ivl, factor, reps, lapses, left, odue, odid, flags, data)
+			 VALUES (?, ?, ?, 0, ?, -1, 0, 0, ?, ?, ?, ?, 0, 0, 0, 0, 0, '')`,
+			cardID, noteID, deckID, now.Unix(), i, card.IntervalDays, int(card.EaseFactor*1000), card.Repetitions,
+		); err != nil {
+			return nil, fmt.Errorf("insert card %d: %w", i, err)
+		}
+
+		// Cards with Repetitions > 0 get one synthetic revlog entry
+		// reflecting their current SM-2 state. We only keep current state,
+		// not a full history of past reviews, so this is one row per card,
+		// not one per review event. ImportAPKG does not read revlog or the
+		// cards' scheduling fields back, so an imported deck starts with no
+		// review state.
+		if card.Repetitions > 0 {
+			if _, err := db.Exec(
+				`INSERT INTO revlog (id, cid, usn, ease, ivl, lastIvl, factor, time, type)
+				 VALUES (?, ?, -1, ?, ?, ?, ?, 0, 1)`,
+				cardID, cardID, ankiEase(card.LastGrade), card.IntervalDays, card.IntervalDays, int(card.EaseFactor*1000),
+			); err != nil {
+				return nil, fmt.Errorf("insert revlog %d: %w", i, err)
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+
+	dbBytes, err := os.ReadFile(dbPath)
+	if err != nil {
+		return nil, fmt.Errorf("read temp db: %w", err)
+	}
+	w, err := zw.Create("collection.anki2")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(dbBytes); err != nil {
+		return nil, err
+	}
+
+	mediaManifest, _ := json.Marshal(map[string]string{})
+	mw, err := zw.Create("media")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := mw.Write(mediaManifest); err != nil {
+		return nil, err
+	}
+
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// deckIDsByTopic assigns each distinct topic a stable deck id, falling
+// back to defaultDeckID ("Default") when a card has no topic.
+func deckIDsByTopic(cards []Card) map[string]int64 {
+	decks := map[string]int64{"": defaultDeckID}
+	next := int64(defaultDeckID + 1)
+	for _, c := range cards {
+		if _, ok := decks[c.Topic]; !ok {
+			decks[c.Topic] = next
+			next++
+		}
+	}
+	return decks
+}
+
+// ankiEase maps our 0-5 SM-2 grade onto Anki's 1 (again) - 4 (easy) revlog
+// ease scale.
+func ankiEase(grade int) int {
+	switch {
+	case grade < 3:
+		return 1
+	case grade == 3:
+		return 2
+	case grade == 4:
+		return 3
+	default:
+		return 4
+	}
+}
+
+// checksum reproduces Anki's note field checksum: the first 8 hex digits
+// of the SHA1 of the field's stripped text, used for fast duplicate scans.
+func checksum(field string) int64 {
+	sum := sha1.Sum([]byte(strings.TrimSpace(field)))
+	var v int64
+	fmt.Sscanf(fmt.Sprintf("%x", sum)[:8], "%x", &v)
+	return v
+}
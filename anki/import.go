@@ -0,0 +1,109 @@
+package anki
+
+import (
+	"archive/zip"
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ImportAPKG reads the notes out of an .apkg archive's collection.anki2,
+// returning one Card per Basic note ("front\x1fback" fields).
+func ImportAPKG(data []byte) ([]Card, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("open apkg zip: %w", err)
+	}
+
+	var collectionFile *zip.File
+	for _, f := range zr.File {
+		if f.Name == "collection.anki2" {
+			collectionFile = f
+			break
+		}
+	}
+	if collectionFile == nil {
+		return nil, fmt.Errorf("collection.anki2 not found in apkg")
+	}
+
+	rc, err := collectionFile.Open()
+	if err != nil {
+		return nil, fmt.Errorf("open collection.anki2: %w", err)
+	}
+	defer rc.Close()
+
+	tmpFile, err := os.CreateTemp("", "import-*.anki2")
+	if err != nil {
+		return nil, fmt.Errorf("create temp db: %w", err)
+	}
+	dbPath := tmpFile.Name()
+	defer os.Remove(dbPath)
+
+	if _, err := tmpFile.ReadFrom(rc); err != nil {
+		tmpFile.Close()
+		return nil, fmt.Errorf("extract collection.anki2: %w", err)
+	}
+	tmpFile.Close()
+
+	db, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		return nil, fmt.Errorf("open sqlite: %w", err)
+	}
+	defer db.Close()
+
+	deckNames, err := readDeckNames(db)
+	if err != nil {
+		return nil, fmt.Errorf("read deck names: %w", err)
+	}
+
+	rows, err := db.Query(`SELECT n.flds, c.did FROM notes n JOIN cards c ON c.nid = n.id`)
+	if err != nil {
+		return nil, fmt.Errorf("query notes: %w", err)
+	}
+	defer rows.Close()
+
+	var cards []Card
+	for rows.Next() {
+		var flds string
+		var did int64
+		if err := rows.Scan(&flds, &did); err != nil {
+			continue
+		}
+		parts := strings.SplitN(flds, "\x1f", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		cards = append(cards, Card{Topic: deckNames[did], Question: parts[0], Answer: parts[1]})
+	}
+	return cards, rows.Err()
+}
+
+// readDeckNames decodes the `col.decks` JSON blob (Anki stores decks as a
+// JSON object keyed by deck id inside the collection row, not a separate
+// table) into a did -> name lookup, so imported cards can be grouped back
+// into the topic BuildAPKG originally grouped them by.
+func readDeckNames(db *sql.DB) (map[int64]string, error) {
+	var decksJSON string
+	if err := db.QueryRow("SELECT decks FROM col").Scan(&decksJSON); err != nil {
+		return nil, fmt.Errorf("query col.decks: %w", err)
+	}
+
+	var raw map[string]struct {
+		ID   int64  `json:"id"`
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal([]byte(decksJSON), &raw); err != nil {
+		return nil, fmt.Errorf("parse col.decks: %w", err)
+	}
+
+	names := make(map[int64]string, len(raw))
+	for _, d := range raw {
+		if d.Name != "Default" {
+			names[d.ID] = d.Name
+		}
+	}
+	return names, nil
+}
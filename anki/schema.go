@@ -0,0 +1,136 @@
+package anki
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// createSchema lays down the standard Anki collection.anki2 tables
+// (schema version 11, the same one `anki` itself writes).
+func createSchema(db *sql.DB) error {
+	stmts := []string{
+		`CREATE TABLE col (
+			id integer PRIMARY KEY,
+			crt integer NOT NULL,
+			mod integer NOT NULL,
+			scm integer NOT NULL,
+			ver integer NOT NULL,
+			dty integer NOT NULL,
+			usn integer NOT NULL,
+			ls integer NOT NULL,
+			conf text NOT NULL,
+			models text NOT NULL,
+			decks text NOT NULL,
+			dconf text NOT NULL,
+			tags text NOT NULL
+		)`,
+		`CREATE TABLE notes (
+			id integer PRIMARY KEY,
+			guid text NOT NULL,
+			mid integer NOT NULL,
+			mod integer NOT NULL,
+			usn integer NOT NULL,
+			tags text NOT NULL,
+			flds text NOT NULL,
+			sfld text NOT NULL,
+			csum integer NOT NULL,
+			flags integer NOT NULL,
+			data text NOT NULL
+		)`,
+		`CREATE TABLE cards (
+			id integer PRIMARY KEY,
+			nid integer NOT NULL,
+			did integer NOT NULL,
+			ord integer NOT NULL,
+			mod integer NOT NULL,
+			usn integer NOT NULL,
+			type integer NOT NULL,
+			queue integer NOT NULL,
+			due integer NOT NULL,
+			ivl integer NOT NULL,
+			factor integer NOT NULL,
+			reps integer NOT NULL,
+			lapses integer NOT NULL,
+			left integer NOT NULL,
+			odue integer NOT NULL,
+			odid integer NOT NULL,
+			flags integer NOT NULL,
+			data text NOT NULL
+		)`,
+		`CREATE TABLE revlog (
+			id integer PRIMARY KEY,
+			cid integer NOT NULL,
+			usn integer NOT NULL,
+			ease integer NOT NULL,
+			ivl integer NOT NULL,
+			lastIvl integer NOT NULL,
+			factor integer NOT NULL,
+			time integer NOT NULL,
+			type integer NOT NULL
+		)`,
+		`CREATE INDEX ix_notes_guid ON notes (guid)`,
+		`CREATE INDEX ix_cards_nid ON cards (nid)`,
+		`CREATE INDEX ix_cards_did ON cards (did)`,
+		`CREATE INDEX ix_revlog_cid ON revlog (cid)`,
+	}
+	for _, stmt := range stmts {
+		if _, err := db.Exec(stmt); err != nil {
+			return fmt.Errorf("exec %q: %w", stmt, err)
+		}
+	}
+	return nil
+}
+
+// writeCollectionRow inserts the single `col` row describing the
+// collection's config, the built-in "Basic" note type, and one deck per
+// topic.
+func writeCollectionRow(db *sql.DB, decks map[string]int64) error {
+	now := time.Now()
+
+	models := map[string]any{
+		fmt.Sprintf("%d", basicModelID): map[string]any{
+			"id":   basicModelID,
+			"name": "Basic",
+			"type": 0,
+			"flds": []map[string]any{
+				{"name": "Front", "ord": 0},
+				{"name": "Back", "ord": 1},
+			},
+			"tmpls": []map[string]any{
+				{"name": "Card 1", "ord": 0, "qfmt": "{{Front}}", "afmt": "{{FrontSide}}<hr id=answer>{{Back}}"},
+			},
+			"css": ".card { font-family: arial; font-size: 20px; text-align: center; }",
+			"mod": now.Unix(),
+			"usn": -1,
+		},
+	}
+
+	deckDefs := map[string]any{}
+	for topic, id := range decks {
+		name := topic
+		if name == "" {
+			name = "Default"
+		}
+		deckDefs[fmt.Sprintf("%d", id)] = map[string]any{
+			"id":   id,
+			"name": name,
+			"mod":  now.Unix(),
+			"usn":  -1,
+		}
+	}
+
+	modelsJSON, _ := json.Marshal(models)
+	decksJSON, _ := json.Marshal(deckDefs)
+	confJSON, _ := json.Marshal(map[string]any{"curDeck": defaultDeckID})
+	dconfJSON, _ := json.Marshal(map[string]any{})
+	tagsJSON, _ := json.Marshal(map[string]any{})
+
+	_, err := db.Exec(
+		`INSERT INTO col (id, crt, mod, scm, ver, dty, usn, ls, conf, models, decks, dconf, tags)
+		 VALUES (1, ?, ?, ?, 11, 0, -1, 0, ?, ?, ?, ?, ?)`,
+		now.Unix(), now.UnixMilli(), now.UnixMilli(), confJSON, modelsJSON, decksJSON, dconfJSON, tagsJSON,
+	)
+	return err
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// CardType identifies which shape a flashcard's payload takes.
+type CardType string
+
+const (
+	CardBasic     CardType = "basic"
+	CardCloze     CardType = "cloze"
+	CardMCQ       CardType = "mcq"
+	CardTrueFalse CardType = "truefalse"
+	CardImage     CardType = "image"
+)
+
+// ClozeItem is one hidden span within a cloze card's text.
+type ClozeItem struct {
+	ID     string `json:"id"`
+	Answer string `json:"answer"`
+	Hint   string `json:"hint,omitempty"`
+}
+
+// ClozePayload is the payload shape for CardCloze.
+type ClozePayload struct {
+	Text   string      `json:"text"`
+	Clozes []ClozeItem `json:"clozes"`
+}
+
+// MCQPayload is the payload shape for CardMCQ and CardTrueFalse.
+type MCQPayload struct {
+	Question     string   `json:"question"`
+	Choices      []string `json:"choices"`
+	CorrectIndex int      `json:"correct_index"`
+	Explanation  string   `json:"explanation,omitempty"`
+}
+
+// ImageRegion is one bounding box occluded on an image card.
+type ImageRegion struct {
+	X      float64 `json:"x"`
+	Y      float64 `json:"y"`
+	Width  float64 `json:"width"`
+	Height float64 `json:"height"`
+	Label  string  `json:"label,omitempty"`
+}
+
+// ImagePayload is the payload shape for CardImage.
+type ImagePayload struct {
+	ImageBase64 string        `json:"image_base64"`
+	Regions     []ImageRegion `json:"regions"`
+}
+
+// promptForType renders the AI instruction asking for the right JSON
+// structure for the given card type. Image cards aren't AI-generated
+// (there's no base image to occlude), so callers should never reach this
+// for CardImage.
+func promptForType(cardType CardType, count int, topic, level string) string {
+	switch cardType {
+	case CardCloze:
+		return fmt.Sprintf(
+			"Generate exactly %d cloze-deletion flashcards for learning %s at %s level. "+
+				"Return ONLY a valid JSON array with this exact format: "+
+				`[{"text": "... {{c1::hidden term}} ...", "clozes": [{"id": "c1", "answer": "hidden term", "hint": "..."}]}]`+
+				". No other text.",
+			count, topic, level,
+		)
+	case CardMCQ:
+		return fmt.Sprintf(
+			"Generate exactly %d multiple-choice flashcards for learning %s at %s level. "+
+				"Return ONLY a valid JSON array with this exact format: "+
+				`[{"question": "...", "choices": ["...", "...", "...", "..."], "correct_index": 0, "explanation": "..."}]`+
+				". No other text.",
+			count, topic, level,
+		)
+	case CardTrueFalse:
+		return fmt.Sprintf(
+			"Generate exactly %d true/false flashcards for learning %s at %s level. "+
+				"Return ONLY a valid JSON array with this exact format: "+
+				`[{"question": "...", "choices": ["True", "False"], "correct_index": 0, "explanation": "..."}]`+
+				". No other text.",
+			count, topic, level,
+		)
+	default:
+		return fmt.Sprintf(
+			"Generate exactly %d high-quality flashcards for learning %s at %s level. Return ONLY a valid JSON array with this exact format: [{\"question\": \"...\", \"answer\": \"...\"}]. No other text.",
+			count, topic, level,
+		)
+	}
+}
+
+// systemPromptForType returns the system message paired with promptForType.
+func systemPromptForType(cardType CardType) string {
+	switch cardType {
+	case CardCloze:
+		return "You are a flashcard generator. Return only a valid JSON array of cloze cards with text and clozes fields."
+	case CardMCQ, CardTrueFalse:
+		return "You are a flashcard generator. Return only a valid JSON array of multiple-choice cards with question, choices, correct_index and explanation fields."
+	default:
+		return "You are a flashcard generator. Return only valid JSON array format with question and answer fields."
+	}
+}
+
+// cardFromRaw maps one AI-produced JSON object into the (question, answer,
+// payload) shape stored in the flashcards table, validating it has the
+// fields expected for cardType. question is always a short human-readable
+// label so /flashcards/:username listings stay uniform across types.
+func cardFromRaw(cardType CardType, raw map[string]any) (question, answer string, payload []byte, ok bool) {
+	switch cardType {
+	case CardCloze:
+		text, _ := raw["text"].(string)
+		clozesRaw, hasC := raw["clozes"]
+		if text == "" || !hasC {
+			return "", "", nil, false
+		}
+		payload, err := json.Marshal(map[string]any{"text": text, "clozes": clozesRaw})
+		if err != nil {
+			return "", "", nil, false
+		}
+		return text, "", payload, true
+
+	case CardMCQ, CardTrueFalse:
+		question, _ := raw["question"].(string)
+		choicesRaw, hasChoices := raw["choices"]
+		if question == "" || !hasChoices {
+			return "", "", nil, false
+		}
+		payload, err := json.Marshal(map[string]any{
+			"question":      question,
+			"choices":       choicesRaw,
+			"correct_index": raw["correct_index"],
+			"explanation":   raw["explanation"],
+		})
+		if err != nil {
+			return "", "", nil, false
+		}
+		return question, "", payload, true
+
+	default:
+		q, hasQ := raw["question"].(string)
+		a, hasA := raw["answer"].(string)
+		if !hasQ || !hasA || q == "" || a == "" {
+			return "", "", nil, false
+		}
+		return q, a, nil, true
+	}
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"strings"
+
+	"github.com/Pranesh-2005/FlashCardGenerator-GoLang/anki"
+	"github.com/gofiber/fiber/v2"
+)
+
+// exportFlashcards handles GET /export/:username?format=apkg|csv|json,
+// letting users move their deck out of the system in whichever shape
+// their downstream tool expects.
+func exportFlashcards(c *fiber.Ctx) error {
+	username := c.Params("username")
+	format := strings.ToLower(c.Query("format", "json"))
+	fmt.Printf("📤 Exporting flashcards for %s as %s\n", username, format)
+
+	rows, err := db.Query(context.Background(),
+		`SELECT f.topic, f.question, f.answer, f.type, f.payload,
+		        r.ease_factor, r.interval_days, r.repetitions, r.last_grade
+		 FROM flashcards f
+		 JOIN users u ON f.user_id = u.id
+		 LEFT JOIN reviews r ON r.card_id = f.id
+		 WHERE u.username=$1 ORDER BY f.created_at`, username)
+	if err != nil {
+		fmt.Printf("❌ Export query error: %v\n", err)
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+	defer rows.Close()
+
+	var cards []anki.Card
+	for rows.Next() {
+		var topic, question, answer, cardType string
+		var payload []byte
+		var ease *float64
+		var interval, reps, lastGrade *int
+		if err := rows.Scan(&topic, &question, &answer, &cardType, &payload, &ease, &interval, &reps, &lastGrade); err != nil {
+			fmt.Printf("❌ Export scan error: %v\n", err)
+			continue
+		}
+		card := anki.Card{
+			Username: username, Topic: topic, Question: question, Answer: answer,
+			Type: cardType, Payload: payload, EaseFactor: defaultEaseFactor,
+		}
+		if ease != nil {
+			card.EaseFactor = *ease
+		}
+		if interval != nil {
+			card.IntervalDays = *interval
+		}
+		if reps != nil {
+			card.Repetitions = *reps
+		}
+		if lastGrade != nil {
+			card.LastGrade = *lastGrade
+		}
+		cards = append(cards, card)
+	}
+
+	switch format {
+	case "csv":
+		var buf strings.Builder
+		w := csv.NewWriter(&buf)
+		w.Write([]string{"question", "answer", "topic"})
+		for _, card := range cards {
+			w.Write([]string{card.Question, card.Answer, card.Topic})
+		}
+		w.Flush()
+		c.Set("Content-Type", "text/csv")
+		c.Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.csv"`, username))
+		return c.SendString(buf.String())
+
+	case "apkg":
+		data, err := anki.BuildAPKG(cards)
+		if err != nil {
+			fmt.Printf("❌ apkg build error: %v\n", err)
+			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+		}
+		c.Set("Content-Type", "application/octet-stream")
+		c.Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.apkg"`, username))
+		return c.Send(data)
+
+	default:
+		return c.JSON(cards)
+	}
+}
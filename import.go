@@ -0,0 +1,138 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha1"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/Pranesh-2005/FlashCardGenerator-GoLang/anki"
+	"github.com/gofiber/fiber/v2"
+)
+
+// importFlashcards handles POST /import?username=...&format=apkg|csv|json,
+// accepting a deck in any of the three formats, deduping against what the
+// user already has, and streaming progress back over SSE so large decks
+// don't look hung.
+func importFlashcards(c *fiber.Ctx) error {
+	username := c.Query("username")
+	format := strings.ToLower(c.Query("format", "json"))
+	fmt.Printf("📥 Importing flashcards for %s from %s\n", username, format)
+
+	if username == "" {
+		return c.Status(400).JSON(fiber.Map{"error": "username is required"})
+	}
+
+	var userID string
+	err := db.QueryRow(context.Background(), "SELECT id FROM users WHERE username=$1", username).Scan(&userID)
+	if err != nil {
+		fmt.Printf("❌ User lookup error: %v\n", err)
+		return c.Status(400).JSON(fiber.Map{"error": "User not found"})
+	}
+
+	var cards []anki.Card
+	switch format {
+	case "apkg":
+		imported, err := anki.ImportAPKG(c.Body())
+		if err != nil {
+			fmt.Printf("❌ apkg import error: %v\n", err)
+			return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+		}
+		cards = imported
+
+	case "csv":
+		r := csv.NewReader(strings.NewReader(string(c.Body())))
+		records, err := r.ReadAll()
+		if err != nil {
+			fmt.Printf("❌ CSV import error: %v\n", err)
+			return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+		}
+		for i, rec := range records {
+			if i == 0 && len(rec) > 0 && strings.EqualFold(rec[0], "question") {
+				continue // header row
+			}
+			if len(rec) < 2 {
+				continue
+			}
+			card := anki.Card{Question: rec[0], Answer: rec[1]}
+			if len(rec) >= 3 {
+				card.Topic = rec[2]
+			}
+			cards = append(cards, card)
+		}
+
+	default:
+		if err := json.Unmarshal(c.Body(), &cards); err != nil {
+			fmt.Printf("❌ JSON import error: %v\n", err)
+			return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+		}
+	}
+
+	fmt.Printf("📦 Parsed %d cards to import for %s\n", len(cards), username)
+
+	existing := make(map[string]bool)
+	rows, err := db.Query(context.Background(),
+		"SELECT question FROM flashcards WHERE user_id=$1", userID)
+	if err != nil {
+		fmt.Printf("❌ Existing-cards query error: %v\n", err)
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+	for rows.Next() {
+		var q string
+		if err := rows.Scan(&q); err == nil {
+			existing[dedupeKey(userID, q)] = true
+		}
+	}
+	rows.Close()
+
+	c.Set("Content-Type", "text/event-stream")
+	c.Set("Cache-Control", "no-cache")
+	c.Set("Connection", "keep-alive")
+
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		inserted, skipped := 0, 0
+		for i, card := range cards {
+			key := dedupeKey(userID, card.Question)
+			if existing[key] {
+				skipped++
+			} else {
+				cardType := card.Type
+				if cardType == "" {
+					cardType = string(CardBasic)
+				}
+				_, err := db.Exec(context.Background(),
+					"INSERT INTO flashcards (user_id, topic, question, answer, type, payload) VALUES ($1, $2, $3, $4, $5, $6)",
+					userID, card.Topic, card.Question, card.Answer, cardType, []byte(card.Payload))
+				if err != nil {
+					fmt.Printf("❌ Insert error for imported card %d: %v\n", i+1, err)
+				} else {
+					existing[key] = true
+					inserted++
+				}
+			}
+
+			if (i+1)%25 == 0 || i == len(cards)-1 {
+				frame, _ := json.Marshal(fiber.Map{"processed": i + 1, "total": len(cards), "inserted": inserted, "skipped": skipped})
+				fmt.Fprintf(w, "event: progress\ndata: %s\n\n", frame)
+				w.Flush()
+			}
+		}
+
+		fmt.Printf("✅ Import complete: %d inserted, %d skipped as duplicates\n", inserted, skipped)
+		done, _ := json.Marshal(fiber.Map{"inserted": inserted, "skipped": skipped})
+		fmt.Fprintf(w, "event: done\ndata: %s\n\n", done)
+		w.Flush()
+	})
+
+	return nil
+}
+
+// dedupeKey identifies a card by (user_id, question) so re-importing the
+// same deck doesn't create duplicates.
+func dedupeKey(userID, question string) string {
+	sum := sha1.Sum([]byte(userID + "\x00" + question))
+	return fmt.Sprintf("%x", sum)
+}
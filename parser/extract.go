@@ -0,0 +1,117 @@
+// Package parser turns raw, possibly malformed LLM output into flashcards,
+// and validates the result against a strict JSON schema before it's allowed
+// anywhere near the database.
+package parser
+
+import "encoding/json"
+
+// RawCard is an unvalidated question/answer pair pulled out of AI output.
+type RawCard struct {
+	Question string `json:"question"`
+	Answer   string `json:"answer"`
+}
+
+// Extract salvages flashcards from raw AI text. It scans byte-by-byte
+// tracking bracket/brace depth and string state (respecting `\"` escapes)
+// to find the outermost balanced `[...]` array. If no balanced array is
+// found, it falls back to collecting every balanced top-level `{...}`
+// fragment and treating each as one card.
+func Extract(raw string) ([]RawCard, error) {
+	if span := outermostArray(raw); span != "" {
+		var cards []RawCard
+		if err := json.Unmarshal([]byte(span), &cards); err == nil {
+			return cards, nil
+		}
+	}
+
+	var cards []RawCard
+	for _, obj := range topLevelObjects(raw) {
+		var card RawCard
+		if err := json.Unmarshal([]byte(obj), &card); err == nil {
+			cards = append(cards, card)
+		}
+	}
+	if len(cards) == 0 {
+		return nil, errNoCards
+	}
+	return cards, nil
+}
+
+var errNoCards = jsonError("no balanced JSON array or objects found in AI output")
+
+type jsonError string
+
+func (e jsonError) Error() string { return string(e) }
+
+// outermostArray returns the text of the first balanced top-level `[...]`
+// span in raw, or "" if none closes.
+func outermostArray(raw string) string {
+	depth := 0
+	start := -1
+	inString := false
+	escaped := false
+
+	for i := 0; i < len(raw); i++ {
+		ch := raw[i]
+		if escaped {
+			escaped = false
+			continue
+		}
+		switch {
+		case inString && ch == '\\':
+			escaped = true
+		case ch == '"':
+			inString = !inString
+		case !inString && ch == '[':
+			if depth == 0 {
+				start = i
+			}
+			depth++
+		case !inString && ch == ']':
+			if depth > 0 {
+				depth--
+				if depth == 0 && start >= 0 {
+					return raw[start : i+1]
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// topLevelObjects returns the text of every balanced top-level `{...}`
+// span in raw, in order.
+func topLevelObjects(raw string) []string {
+	var objs []string
+	depth := 0
+	start := -1
+	inString := false
+	escaped := false
+
+	for i := 0; i < len(raw); i++ {
+		ch := raw[i]
+		if escaped {
+			escaped = false
+			continue
+		}
+		switch {
+		case inString && ch == '\\':
+			escaped = true
+		case ch == '"':
+			inString = !inString
+		case !inString && ch == '{':
+			if depth == 0 {
+				start = i
+			}
+			depth++
+		case !inString && ch == '}':
+			if depth > 0 {
+				depth--
+				if depth == 0 && start >= 0 {
+					objs = append(objs, raw[start:i+1])
+				}
+			}
+		}
+	}
+	return objs
+}
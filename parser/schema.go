@@ -0,0 +1,87 @@
+package parser
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/xeipuuv/gojsonschema"
+)
+
+const maxQuestionLen = 500
+
+// cardSchema is the strict JSON schema a single flashcard must satisfy
+// before it's inserted. The response_format schema built by
+// ResponseFormatJSONSchema describes the same fields and question length
+// limit, but not the minLength checks, which are enforced only here.
+const cardSchema = `{
+  "type": "object",
+  "properties": {
+    "question": {"type": "string", "minLength": 1, "maxLength": 500},
+    "answer": {"type": "string", "minLength": 1}
+  },
+  "required": ["question", "answer"],
+  "additionalProperties": false
+}`
+
+var cardSchemaLoader = gojsonschema.NewStringLoader(cardSchema)
+
+// ValidationError reports why a single card (by its position in the
+// batch) failed validation.
+type ValidationError struct {
+	Index  int    `json:"index"`
+	Reason string `json:"reason"`
+}
+
+// Validate checks each card against cardSchema, returning the cards that
+// passed and one ValidationError per card that didn't. A batch is never
+// failed wholesale for a handful of bad cards.
+func Validate(cards []RawCard) ([]RawCard, []ValidationError) {
+	var valid []RawCard
+	var errs []ValidationError
+
+	for i, card := range cards {
+		if reason, ok := validateOne(card); !ok {
+			errs = append(errs, ValidationError{Index: i, Reason: reason})
+			continue
+		}
+		valid = append(valid, card)
+	}
+
+	return valid, errs
+}
+
+func validateOne(card RawCard) (string, bool) {
+	doc, _ := json.Marshal(card)
+	result, err := gojsonschema.Validate(cardSchemaLoader, gojsonschema.NewBytesLoader(doc))
+	if err != nil {
+		return fmt.Sprintf("schema validation error: %v", err), false
+	}
+	if !result.Valid() {
+		return result.Errors()[0].String(), false
+	}
+	return "", true
+}
+
+// ResponseFormatJSONSchema returns the OpenRouter/OpenAI `response_format`
+// payload requesting a strict array of {question, answer} objects.
+func ResponseFormatJSONSchema() map[string]any {
+	return map[string]any{
+		"type": "json_schema",
+		"json_schema": map[string]any{
+			"name":   "flashcards",
+			"strict": true,
+			"schema": map[string]any{
+				"type": "array",
+				"items": map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"question": map[string]any{"type": "string", "maxLength": maxQuestionLen},
+						"answer":   map[string]any{"type": "string"},
+					},
+					"required":             []string{"question", "answer"},
+					"additionalProperties": false,
+				},
+			},
+		},
+	}
+}
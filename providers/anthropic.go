@@ -0,0 +1,81 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/valyala/fasthttp"
+)
+
+const anthropicURL = "https://api.anthropic.com/v1/messages"
+
+// Anthropic generates flashcards via the Claude Messages API.
+type Anthropic struct {
+	APIKey string
+	Model  string
+}
+
+// NewAnthropicFromEnv builds an Anthropic provider from ANTHROPIC_API_KEY
+// and ANTHROPIC_MODEL (defaulting to claude-3-5-haiku-latest).
+func NewAnthropicFromEnv() *Anthropic {
+	model := os.Getenv("ANTHROPIC_MODEL")
+	if model == "" {
+		model = "claude-3-5-haiku-latest"
+	}
+	return &Anthropic{APIKey: os.Getenv("ANTHROPIC_API_KEY"), Model: model}
+}
+
+func (p *Anthropic) Name() string { return "anthropic" }
+
+type anthropicRequest struct {
+	Model     string        `json:"model"`
+	MaxTokens int           `json:"max_tokens"`
+	System    string        `json:"system"`
+	Messages  []chatMessage `json:"messages"`
+}
+
+type anthropicResponse struct {
+	Content []struct {
+		Text string `json:"text"`
+	} `json:"content"`
+}
+
+func (p *Anthropic) Generate(ctx context.Context, prompt string, opts Options) ([]Flashcard, error) {
+	body, _ := json.Marshal(anthropicRequest{
+		Model:     p.Model,
+		MaxTokens: 2048,
+		System:    "You are a flashcard generator. Return only valid JSON array format with question and answer fields.",
+		Messages:  []chatMessage{{Role: "user", Content: prompt}},
+	})
+
+	req := fasthttp.AcquireRequest()
+	defer fasthttp.ReleaseRequest(req)
+	req.SetRequestURI(anthropicURL)
+	req.Header.SetMethod("POST")
+	req.Header.Set("x-api-key", p.APIKey)
+	req.Header.Set("anthropic-version", "2023-06-01")
+	req.Header.Set("Content-Type", "application/json")
+	req.SetBody(body)
+
+	resp := fasthttp.AcquireResponse()
+	defer fasthttp.ReleaseResponse(resp)
+
+	client := &fasthttp.Client{}
+	if err := doWithContext(ctx, client, req, resp); err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	if resp.StatusCode() >= 400 {
+		return nil, fmt.Errorf("status %d: %s", resp.StatusCode(), resp.Body())
+	}
+
+	var parsed anthropicResponse
+	if err := json.Unmarshal(resp.Body(), &parsed); err != nil {
+		return nil, fmt.Errorf("invalid response: %w", err)
+	}
+	if len(parsed.Content) == 0 {
+		return nil, fmt.Errorf("no content returned")
+	}
+	return parseCards(parsed.Content[0].Text)
+}
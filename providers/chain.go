@@ -0,0 +1,174 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+)
+
+// breakerState is the circuit breaker state for a single provider.
+type breakerState int
+
+const (
+	closed breakerState = iota
+	open
+	halfOpen
+)
+
+// breaker is a simple consecutive-failure circuit breaker: it opens after
+// failureThreshold consecutive failures and allows a single half-open probe
+// once cooldown has elapsed.
+type breaker struct {
+	mu               sync.Mutex
+	state            breakerState
+	consecutiveFails int
+	openedAt         time.Time
+	failureThreshold int
+	cooldown         time.Duration
+}
+
+func newBreaker(failureThreshold int, cooldown time.Duration) *breaker {
+	return &breaker{failureThreshold: failureThreshold, cooldown: cooldown}
+}
+
+// allow reports whether a call may proceed, flipping open->halfOpen once
+// the cooldown has elapsed.
+func (b *breaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case open:
+		if time.Since(b.openedAt) >= b.cooldown {
+			b.state = halfOpen
+			return true
+		}
+		return false
+	case halfOpen:
+		// A probe is already in flight; hold other callers off until it
+		// records a success or failure.
+		return false
+	default:
+		return true
+	}
+}
+
+func (b *breaker) recordSuccess() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.consecutiveFails = 0
+	b.state = closed
+}
+
+func (b *breaker) recordFailure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.consecutiveFails++
+	if b.state == halfOpen || b.consecutiveFails >= b.failureThreshold {
+		b.state = open
+		b.openedAt = time.Now()
+	}
+}
+
+const (
+	defaultFailureThreshold = 3
+	defaultCooldown         = 30 * time.Second
+	defaultMaxRetries       = 2
+	defaultBaseBackoff      = 250 * time.Millisecond
+)
+
+// chainMember pairs a Generator with its own circuit breaker.
+type chainMember struct {
+	gen     Generator
+	breaker *breaker
+}
+
+// Chain tries a sequence of Generators in order, skipping providers whose
+// circuit breaker is open and retrying transient failures with exponential
+// backoff and jitter before moving to the next provider.
+type Chain struct {
+	members []chainMember
+}
+
+// NewChain builds a Chain that attempts providers in the given order.
+func NewChain(generators ...Generator) *Chain {
+	members := make([]chainMember, len(generators))
+	for i, g := range generators {
+		members[i] = chainMember{gen: g, breaker: newBreaker(defaultFailureThreshold, defaultCooldown)}
+	}
+	return &Chain{members: members}
+}
+
+// Result is the outcome of a successful Chain.Generate call, naming the
+// provider that actually produced the cards.
+type Result struct {
+	Provider   string
+	Flashcards []Flashcard
+}
+
+// Generate tries each provider in order. Within a provider, retryable
+// errors (429, 5xx, timeouts) are retried with exponential backoff and
+// jitter up to defaultMaxRetries times before falling through to the next
+// provider in the chain.
+func (c *Chain) Generate(ctx context.Context, prompt string, opts Options) (*Result, error) {
+	if len(c.members) == 0 {
+		return nil, fmt.Errorf("no providers configured")
+	}
+
+	var errs []string
+	for _, m := range c.members {
+		if !m.breaker.allow() {
+			errs = append(errs, fmt.Sprintf("%s: circuit open", m.gen.Name()))
+			continue
+		}
+
+		cards, err := generateWithRetry(ctx, m.gen, prompt, opts)
+		if err == nil {
+			m.breaker.recordSuccess()
+			return &Result{Provider: m.gen.Name(), Flashcards: cards}, nil
+		}
+
+		m.breaker.recordFailure()
+		errs = append(errs, fmt.Sprintf("%s: %v", m.gen.Name(), err))
+	}
+
+	return nil, fmt.Errorf("all providers failed: %s", strings.Join(errs, "; "))
+}
+
+func generateWithRetry(ctx context.Context, gen Generator, prompt string, opts Options) ([]Flashcard, error) {
+	var lastErr error
+	for attempt := 0; attempt <= defaultMaxRetries; attempt++ {
+		if attempt > 0 {
+			backoff := defaultBaseBackoff * time.Duration(1<<uint(attempt-1))
+			jitter := time.Duration(rand.Int63n(int64(backoff) / 2))
+			select {
+			case <-time.After(backoff + jitter):
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+
+		cards, err := gen.Generate(ctx, prompt, opts)
+		if err == nil {
+			return cards, nil
+		}
+		lastErr = err
+		if !isRetryable(err) {
+			return nil, err
+		}
+	}
+	return nil, lastErr
+}
+
+func isRetryable(err error) bool {
+	msg := err.Error()
+	for _, marker := range []string{"status 429", "status 5", "timeout", "request failed"} {
+		if strings.Contains(msg, marker) {
+			return true
+		}
+	}
+	return false
+}
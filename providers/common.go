@@ -0,0 +1,113 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/Pranesh-2005/FlashCardGenerator-GoLang/parser"
+	"github.com/valyala/fasthttp"
+)
+
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type chatRequest struct {
+	Model          string         `json:"model"`
+	Messages       []chatMessage  `json:"messages"`
+	ResponseFormat map[string]any `json:"response_format,omitempty"`
+}
+
+type chatResponse struct {
+	Choices []struct {
+		Message struct {
+			Content string `json:"content"`
+		} `json:"message"`
+	} `json:"choices"`
+}
+
+// doWithContext runs req through client, honoring ctx: an already-expired
+// ctx fails fast, and a ctx deadline is handed to fasthttp so a hung
+// provider can't block past it.
+func doWithContext(ctx context.Context, client *fasthttp.Client, req *fasthttp.Request, resp *fasthttp.Response) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		return client.DoDeadline(req, resp, deadline)
+	}
+	return client.Do(req, resp)
+}
+
+// callChatCompletions performs an OpenAI-schema chat completion request
+// against url using apiKey and model, and returns the raw assistant content.
+// When useJSONSchema is set, the request asks for a strict json_schema
+// response_format so well-behaved models never produce anything else.
+func callChatCompletions(ctx context.Context, url, apiKey, model, systemPrompt, userPrompt string, useJSONSchema bool) (string, error) {
+	creq := chatRequest{
+		Model: model,
+		Messages: []chatMessage{
+			{Role: "system", Content: systemPrompt},
+			{Role: "user", Content: userPrompt},
+		},
+	}
+	if useJSONSchema {
+		creq.ResponseFormat = parser.ResponseFormatJSONSchema()
+	}
+	body, _ := json.Marshal(creq)
+
+	req := fasthttp.AcquireRequest()
+	defer fasthttp.ReleaseRequest(req)
+	req.SetRequestURI(url)
+	req.Header.SetMethod("POST")
+	req.Header.Set("Authorization", "Bearer "+apiKey)
+	req.Header.Set("Content-Type", "application/json")
+	req.SetBody(body)
+
+	resp := fasthttp.AcquireResponse()
+	defer fasthttp.ReleaseResponse(resp)
+
+	client := &fasthttp.Client{}
+	if err := doWithContext(ctx, client, req, resp); err != nil {
+		return "", fmt.Errorf("request failed: %w", err)
+	}
+	if resp.StatusCode() >= 400 {
+		return "", fmt.Errorf("status %d: %s", resp.StatusCode(), resp.Body())
+	}
+
+	var parsed chatResponse
+	if err := json.Unmarshal(resp.Body(), &parsed); err != nil {
+		return "", fmt.Errorf("invalid response: %w", err)
+	}
+	if len(parsed.Choices) == 0 {
+		return "", fmt.Errorf("no choices returned")
+	}
+	return parsed.Choices[0].Message.Content, nil
+}
+
+// parseCards salvages flashcards out of raw AI text and validates each one
+// against the strict card schema. Cards that fail validation are dropped
+// individually rather than failing the whole batch; parseCards only
+// returns an error when nothing usable survives.
+func parseCards(raw string) ([]Flashcard, error) {
+	rawCards, err := parser.Extract(raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse flashcards: %w", err)
+	}
+
+	valid, errs := parser.Validate(rawCards)
+	for _, e := range errs {
+		fmt.Printf("⚠️ dropping invalid card %d: %s\n", e.Index, e.Reason)
+	}
+	if len(valid) == 0 {
+		return nil, fmt.Errorf("no cards survived schema validation")
+	}
+
+	cards := make([]Flashcard, len(valid))
+	for i, v := range valid {
+		cards[i] = Flashcard{Question: v.Question, Answer: v.Answer}
+	}
+	return cards, nil
+}
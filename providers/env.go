@@ -0,0 +1,30 @@
+package providers
+
+import "strings"
+
+// LoadChainFromEnv builds a Chain from the LLM_PROVIDERS env var, a
+// comma-separated list of provider names in fallback order (e.g.
+// "openrouter,groq,openai"). Unknown names are skipped. Defaults to
+// openrouter alone when unset.
+func LoadChainFromEnv(llmProviders string) *Chain {
+	names := strings.Split(llmProviders, ",")
+	var gens []Generator
+	for _, n := range names {
+		switch strings.TrimSpace(strings.ToLower(n)) {
+		case "openrouter":
+			gens = append(gens, NewOpenRouterFromEnv())
+		case "openai":
+			gens = append(gens, NewOpenAIFromEnv())
+		case "anthropic":
+			gens = append(gens, NewAnthropicFromEnv())
+		case "ollama":
+			gens = append(gens, NewOllamaFromEnv())
+		case "groq":
+			gens = append(gens, NewGroqFromEnv())
+		}
+	}
+	if len(gens) == 0 {
+		gens = append(gens, NewOpenRouterFromEnv())
+	}
+	return NewChain(gens...)
+}
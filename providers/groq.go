@@ -0,0 +1,36 @@
+package providers
+
+import (
+	"context"
+	"os"
+)
+
+const groqURL = "https://api.groq.com/openai/v1/chat/completions"
+
+// Groq generates flashcards via Groq's OpenAI-compatible chat completions API.
+type Groq struct {
+	APIKey string
+	Model  string
+}
+
+// NewGroqFromEnv builds a Groq provider from GROQ_API_KEY and GROQ_MODEL
+// (defaulting to llama-3.1-8b-instant).
+func NewGroqFromEnv() *Groq {
+	model := os.Getenv("GROQ_MODEL")
+	if model == "" {
+		model = "llama-3.1-8b-instant"
+	}
+	return &Groq{APIKey: os.Getenv("GROQ_API_KEY"), Model: model}
+}
+
+func (p *Groq) Name() string { return "groq" }
+
+func (p *Groq) Generate(ctx context.Context, prompt string, opts Options) ([]Flashcard, error) {
+	raw, err := callChatCompletions(ctx, groqURL, p.APIKey, p.Model,
+		"You are a flashcard generator. Return only valid JSON array format with question and answer fields.",
+		prompt, false)
+	if err != nil {
+		return nil, err
+	}
+	return parseCards(raw)
+}
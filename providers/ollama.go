@@ -0,0 +1,79 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/valyala/fasthttp"
+)
+
+// Ollama generates flashcards via a local Ollama server's chat API.
+type Ollama struct {
+	Host  string
+	Model string
+}
+
+// NewOllamaFromEnv builds an Ollama provider from OLLAMA_HOST (defaulting
+// to http://localhost:11434) and OLLAMA_MODEL (defaulting to llama3.1).
+func NewOllamaFromEnv() *Ollama {
+	host := os.Getenv("OLLAMA_HOST")
+	if host == "" {
+		host = "http://localhost:11434"
+	}
+	model := os.Getenv("OLLAMA_MODEL")
+	if model == "" {
+		model = "llama3.1"
+	}
+	return &Ollama{Host: host, Model: model}
+}
+
+func (p *Ollama) Name() string { return "ollama" }
+
+type ollamaRequest struct {
+	Model    string        `json:"model"`
+	Messages []chatMessage `json:"messages"`
+	Stream   bool          `json:"stream"`
+}
+
+type ollamaResponse struct {
+	Message struct {
+		Content string `json:"content"`
+	} `json:"message"`
+}
+
+func (p *Ollama) Generate(ctx context.Context, prompt string, opts Options) ([]Flashcard, error) {
+	body, _ := json.Marshal(ollamaRequest{
+		Model: p.Model,
+		Messages: []chatMessage{
+			{Role: "system", Content: "You are a flashcard generator. Return only valid JSON array format with question and answer fields."},
+			{Role: "user", Content: prompt},
+		},
+		Stream: false,
+	})
+
+	req := fasthttp.AcquireRequest()
+	defer fasthttp.ReleaseRequest(req)
+	req.SetRequestURI(p.Host + "/api/chat")
+	req.Header.SetMethod("POST")
+	req.Header.Set("Content-Type", "application/json")
+	req.SetBody(body)
+
+	resp := fasthttp.AcquireResponse()
+	defer fasthttp.ReleaseResponse(resp)
+
+	client := &fasthttp.Client{}
+	if err := doWithContext(ctx, client, req, resp); err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	if resp.StatusCode() >= 400 {
+		return nil, fmt.Errorf("status %d: %s", resp.StatusCode(), resp.Body())
+	}
+
+	var parsed ollamaResponse
+	if err := json.Unmarshal(resp.Body(), &parsed); err != nil {
+		return nil, fmt.Errorf("invalid response: %w", err)
+	}
+	return parseCards(parsed.Message.Content)
+}
@@ -0,0 +1,36 @@
+package providers
+
+import (
+	"context"
+	"os"
+)
+
+const openAIURL = "https://api.openai.com/v1/chat/completions"
+
+// OpenAI generates flashcards via OpenAI's chat completions API.
+type OpenAI struct {
+	APIKey string
+	Model  string
+}
+
+// NewOpenAIFromEnv builds an OpenAI provider from OPENAI_API_KEY and
+// OPENAI_MODEL (defaulting to gpt-4o-mini).
+func NewOpenAIFromEnv() *OpenAI {
+	model := os.Getenv("OPENAI_MODEL")
+	if model == "" {
+		model = "gpt-4o-mini"
+	}
+	return &OpenAI{APIKey: os.Getenv("OPENAI_API_KEY"), Model: model}
+}
+
+func (p *OpenAI) Name() string { return "openai" }
+
+func (p *OpenAI) Generate(ctx context.Context, prompt string, opts Options) ([]Flashcard, error) {
+	raw, err := callChatCompletions(ctx, openAIURL, p.APIKey, p.Model,
+		"You are a flashcard generator. Return only valid JSON array format with question and answer fields.",
+		prompt, false)
+	if err != nil {
+		return nil, err
+	}
+	return parseCards(raw)
+}
@@ -0,0 +1,36 @@
+package providers
+
+import (
+	"context"
+	"os"
+)
+
+const openRouterURL = "https://openrouter.ai/api/v1/chat/completions"
+
+// OpenRouter generates flashcards via OpenRouter's OpenAI-compatible API.
+type OpenRouter struct {
+	APIKey string
+	Model  string
+}
+
+// NewOpenRouterFromEnv builds an OpenRouter provider from OPENROUTER_API_KEY
+// and OPENROUTER_MODEL (defaulting to the free deepseek model).
+func NewOpenRouterFromEnv() *OpenRouter {
+	model := os.Getenv("OPENROUTER_MODEL")
+	if model == "" {
+		model = "deepseek/deepseek-chat-v3.1:free"
+	}
+	return &OpenRouter{APIKey: os.Getenv("OPENROUTER_API_KEY"), Model: model}
+}
+
+func (p *OpenRouter) Name() string { return "openrouter" }
+
+func (p *OpenRouter) Generate(ctx context.Context, prompt string, opts Options) ([]Flashcard, error) {
+	raw, err := callChatCompletions(ctx, openRouterURL, p.APIKey, p.Model,
+		"You are a flashcard generator. Return only valid JSON array format with question and answer fields.",
+		prompt, true)
+	if err != nil {
+		return nil, err
+	}
+	return parseCards(raw)
+}
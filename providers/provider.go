@@ -0,0 +1,28 @@
+// Package providers abstracts flashcard generation over multiple LLM
+// backends (OpenRouter, OpenAI, Anthropic, Ollama, Groq) behind a single
+// Generator interface, with a Chain wrapper for fallback and retries.
+package providers
+
+import "context"
+
+// Flashcard is the provider-agnostic shape a Generator produces.
+type Flashcard struct {
+	Question string `json:"question"`
+	Answer   string `json:"answer"`
+}
+
+// Options describes the generation request (topic, count, level). The
+// built-in Generators send the prompt as given and do not read Options.
+type Options struct {
+	Topic string
+	Count int
+	Level string
+}
+
+// Generator produces flashcards from a single LLM backend.
+type Generator interface {
+	// Name identifies the provider, e.g. "openrouter" or "ollama".
+	Name() string
+	// Generate calls the backend and returns parsed flashcards.
+	Generate(ctx context.Context, prompt string, opts Options) ([]Flashcard, error)
+}
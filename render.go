@@ -0,0 +1,122 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// renderFlashcard handles POST /flashcards/render, taking a card row
+// (question, answer, type, payload) and returning pre-rendered front/back
+// HTML so any frontend can display every card type uniformly without
+// knowing its internal shape.
+func renderFlashcard(c *fiber.Ctx) error {
+	var card Flashcard
+	if err := c.BodyParser(&card); err != nil {
+		fmt.Printf("❌ Invalid render request: %v\n", err)
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request"})
+	}
+
+	front, back, err := renderCard(CardType(card.Type), card)
+	if err != nil {
+		fmt.Printf("❌ Render error: %v\n", err)
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{"front": front, "back": back})
+}
+
+func renderCard(cardType CardType, card Flashcard) (front, back string, err error) {
+	switch cardType {
+	case CardCloze:
+		var payload ClozePayload
+		if err := json.Unmarshal(card.Payload, &payload); err != nil {
+			return "", "", fmt.Errorf("invalid cloze payload: %w", err)
+		}
+		return renderCloze(payload)
+
+	case CardMCQ, CardTrueFalse:
+		var payload MCQPayload
+		if err := json.Unmarshal(card.Payload, &payload); err != nil {
+			return "", "", fmt.Errorf("invalid mcq payload: %w", err)
+		}
+		return renderMCQ(payload), renderMCQAnswer(payload), nil
+
+	case CardImage:
+		var payload ImagePayload
+		if err := json.Unmarshal(card.Payload, &payload); err != nil {
+			return "", "", fmt.Errorf("invalid image payload: %w", err)
+		}
+		return renderImage(payload)
+
+	default:
+		return fmt.Sprintf("<div class=\"front\">%s</div>", html.EscapeString(card.Question)),
+			fmt.Sprintf("<div class=\"back\">%s</div>", html.EscapeString(card.Answer)), nil
+	}
+}
+
+func renderCloze(payload ClozePayload) (front, back string, err error) {
+	escaped := html.EscapeString(payload.Text)
+	frontText, backText := escaped, escaped
+	for _, item := range payload.Clozes {
+		// The marker is matched against the already-escaped text, so its
+		// own id/answer must be escaped the same way to line up.
+		marker := fmt.Sprintf("{{%s::%s}}", html.EscapeString(item.ID), html.EscapeString(item.Answer))
+		hidden := "[...]"
+		if item.Hint != "" {
+			hidden = fmt.Sprintf("[%s]", html.EscapeString(item.Hint))
+		}
+		frontText = strings.ReplaceAll(frontText, marker, hidden)
+		backText = strings.ReplaceAll(backText, marker, fmt.Sprintf("<b>%s</b>", html.EscapeString(item.Answer)))
+	}
+	return fmt.Sprintf("<div class=\"front\">%s</div>", frontText),
+		fmt.Sprintf("<div class=\"back\">%s</div>", backText), nil
+}
+
+func renderMCQ(payload MCQPayload) string {
+	var choices strings.Builder
+	for i, choice := range payload.Choices {
+		choices.WriteString(fmt.Sprintf("<li data-index=\"%d\">%s</li>", i, html.EscapeString(choice)))
+	}
+	return fmt.Sprintf("<div class=\"front\"><p>%s</p><ul>%s</ul></div>", html.EscapeString(payload.Question), choices.String())
+}
+
+func renderMCQAnswer(payload MCQPayload) string {
+	correct := ""
+	if payload.CorrectIndex >= 0 && payload.CorrectIndex < len(payload.Choices) {
+		correct = payload.Choices[payload.CorrectIndex]
+	}
+	back := fmt.Sprintf("<div class=\"back\"><p>Correct: %s</p>", html.EscapeString(correct))
+	if payload.Explanation != "" {
+		back += fmt.Sprintf("<p>%s</p>", html.EscapeString(payload.Explanation))
+	}
+	return back + "</div>"
+}
+
+func renderImage(payload ImagePayload) (front, back string, err error) {
+	// payload.ImageBase64 is client-supplied and stored verbatim, so it must
+	// be confirmed to actually be base64 (never quotes/angle-brackets) before
+	// it's embedded in an HTML attribute.
+	if _, err := base64.StdEncoding.DecodeString(payload.ImageBase64); err != nil {
+		return "", "", fmt.Errorf("invalid image_base64: %w", err)
+	}
+
+	var boxes strings.Builder
+	for _, r := range payload.Regions {
+		boxes.WriteString(fmt.Sprintf(
+			`<div class="occlusion" style="left:%.2f%%;top:%.2f%%;width:%.2f%%;height:%.2f%%"></div>`,
+			r.X, r.Y, r.Width, r.Height))
+	}
+	front = fmt.Sprintf(`<div class="front"><img src="data:image/png;base64,%s"/>%s</div>`, html.EscapeString(payload.ImageBase64), boxes.String())
+
+	var labels strings.Builder
+	for _, r := range payload.Regions {
+		labels.WriteString(fmt.Sprintf("<li>%s</li>", html.EscapeString(r.Label)))
+	}
+	back = fmt.Sprintf(`<div class="back"><img src="data:image/png;base64,%s"/><ul>%s</ul></div>`, html.EscapeString(payload.ImageBase64), labels.String())
+	return front, back, nil
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Review tracks the SM-2 scheduling state for a single flashcard.
+type Review struct {
+	CardID       string    `json:"card_id"`
+	EaseFactor   float64   `json:"ease_factor"`
+	IntervalDays int       `json:"interval_days"`
+	Repetitions  int       `json:"repetitions"`
+	NextReviewAt time.Time `json:"next_review_at"`
+	LastGrade    int       `json:"last_grade"`
+}
+
+// DueCard is a flashcard joined with its current review state, as returned
+// by the /due endpoint.
+type DueCard struct {
+	Flashcard
+	EaseFactor   float64   `json:"ease_factor"`
+	IntervalDays int       `json:"interval_days"`
+	Repetitions  int       `json:"repetitions"`
+	NextReviewAt time.Time `json:"next_review_at"`
+}
+
+const defaultEaseFactor = 2.5
+
+// applySM2 computes the next review state given the previous state and a
+// 0-5 quality grade, per the SuperMemo SM-2 algorithm.
+func applySM2(prev Review, grade int) Review {
+	next := prev
+	next.LastGrade = grade
+
+	if grade < 3 {
+		next.Repetitions = 0
+		next.IntervalDays = 1
+	} else {
+		switch next.Repetitions {
+		case 0:
+			next.IntervalDays = 1
+		case 1:
+			next.IntervalDays = 6
+		default:
+			next.IntervalDays = int(math.Round(float64(prev.IntervalDays) * prev.EaseFactor))
+		}
+		next.Repetitions++
+	}
+
+	ef := prev.EaseFactor + (0.1 - float64(5-grade)*(0.08+float64(5-grade)*0.02))
+	if ef < 1.3 {
+		ef = 1.3
+	}
+	next.EaseFactor = ef
+	next.NextReviewAt = time.Now().Add(time.Duration(next.IntervalDays) * 24 * time.Hour)
+
+	return next
+}
+
+// getDueFlashcards returns GET /flashcards/:username/due — cards never
+// reviewed or whose next_review_at has passed; never-reviewed cards come
+// first, then the rest by next_review_at, earliest first.
+func getDueFlashcards(c *fiber.Ctx) error {
+	username := c.Params("username")
+	fmt.Printf("📅 Loading due flashcards for user: %s\n", username)
+
+	rows, err := db.Query(context.Background(),
+		`SELECT f.id, f.topic, f.question, f.answer, f.type, f.payload,
+		        r.ease_factor, r.interval_days, r.repetitions, r.next_review_at
+		 FROM flashcards f
+		 JOIN users u ON f.user_id = u.id
+		 LEFT JOIN reviews r ON r.card_id = f.id
+		 WHERE u.username=$1 AND (r.next_review_at IS NULL OR r.next_review_at <= now())
+		 ORDER BY r.next_review_at ASC NULLS FIRST`, username)
+	if err != nil {
+		fmt.Printf("❌ Due query error: %v\n", err)
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+	defer rows.Close()
+
+	var due []DueCard
+	for rows.Next() {
+		var d DueCard
+		var payload []byte
+		var ease *float64
+		var interval *int
+		var reps *int
+		var next *time.Time
+		if err := rows.Scan(&d.ID, &d.Topic, &d.Question, &d.Answer, &d.Type, &payload, &ease, &interval, &reps, &next); err != nil {
+			fmt.Printf("❌ Due scan error: %v\n", err)
+			continue
+		}
+		d.Payload = payload
+		if ease != nil {
+			d.EaseFactor = *ease
+		} else {
+			d.EaseFactor = defaultEaseFactor
+		}
+		if interval != nil {
+			d.IntervalDays = *interval
+		}
+		if reps != nil {
+			d.Repetitions = *reps
+		}
+		if next != nil {
+			d.NextReviewAt = *next
+		}
+		due = append(due, d)
+	}
+
+	fmt.Printf("✅ Found %d due cards for %s\n", len(due), username)
+	return c.JSON(due)
+}
+
+// reviewFlashcard handles POST /flashcards/:id/review, grading a card and
+// persisting its next SM-2 schedule.
+func reviewFlashcard(c *fiber.Ctx) error {
+	cardID := c.Params("id")
+
+	type Req struct {
+		Grade int `json:"grade"`
+	}
+	var body Req
+	if err := c.BodyParser(&body); err != nil || body.Grade < 0 || body.Grade > 5 {
+		fmt.Printf("❌ Invalid review request for card %s: %v\n", cardID, err)
+		return c.Status(400).JSON(fiber.Map{"error": "grade must be an integer 0-5"})
+	}
+
+	fmt.Printf("🧠 Reviewing card %s with grade %d\n", cardID, body.Grade)
+
+	var prev Review
+	err := db.QueryRow(context.Background(),
+		"SELECT ease_factor, interval_days, repetitions, last_grade FROM reviews WHERE card_id=$1",
+		cardID).Scan(&prev.EaseFactor, &prev.IntervalDays, &prev.Repetitions, &prev.LastGrade)
+	if err != nil {
+		fmt.Printf("ℹ️ No existing review state for card %s, starting fresh\n", cardID)
+		prev = Review{EaseFactor: defaultEaseFactor}
+	}
+
+	next := applySM2(prev, body.Grade)
+	next.CardID = cardID
+
+	_, err = db.Exec(context.Background(),
+		`INSERT INTO reviews (card_id, ease_factor, interval_days, repetitions, next_review_at, last_grade)
+		 VALUES ($1, $2, $3, $4, $5, $6)
+		 ON CONFLICT (card_id) DO UPDATE SET
+		   ease_factor=EXCLUDED.ease_factor, interval_days=EXCLUDED.interval_days,
+		   repetitions=EXCLUDED.repetitions, next_review_at=EXCLUDED.next_review_at,
+		   last_grade=EXCLUDED.last_grade`,
+		cardID, next.EaseFactor, next.IntervalDays, next.Repetitions, next.NextReviewAt, next.LastGrade)
+	if err != nil {
+		fmt.Printf("❌ Failed to persist review for card %s: %v\n", cardID, err)
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	fmt.Printf("✅ Card %s scheduled for %s (interval=%dd, ef=%.2f)\n",
+		cardID, next.NextReviewAt.Format(time.RFC3339), next.IntervalDays, next.EaseFactor)
+	return c.JSON(next)
+}
+
+// getStats handles GET /stats/:username, summarizing review progress.
+func getStats(c *fiber.Ctx) error {
+	username := c.Params("username")
+	fmt.Printf("📊 Computing stats for user: %s\n", username)
+
+	var total, due, mature int
+	err := db.QueryRow(context.Background(),
+		`SELECT
+		   COUNT(*) AS total,
+		   COUNT(*) FILTER (WHERE r.next_review_at IS NULL OR r.next_review_at <= now()) AS due,
+		   COUNT(*) FILTER (WHERE r.interval_days >= 21) AS mature
+		 FROM flashcards f
+		 JOIN users u ON f.user_id = u.id
+		 LEFT JOIN reviews r ON r.card_id = f.id
+		 WHERE u.username=$1`, username).Scan(&total, &due, &mature)
+	if err != nil {
+		fmt.Printf("❌ Stats query error: %v\n", err)
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	learning := total - mature
+	fmt.Printf("✅ Stats for %s: total=%d due=%d mature=%d learning=%d\n", username, total, due, mature, learning)
+	return c.JSON(fiber.Map{
+		"total":    total,
+		"due":      due,
+		"mature":   mature,
+		"learning": learning,
+	})
+}
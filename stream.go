@@ -0,0 +1,331 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/Pranesh-2005/FlashCardGenerator-GoLang/parser"
+	"github.com/Pranesh-2005/FlashCardGenerator-GoLang/providers"
+	"github.com/gofiber/fiber/v2"
+	"github.com/valyala/fasthttp"
+)
+
+// openRouterStreamChunk is one "data: {...}" frame of an OpenAI-style SSE
+// completion stream.
+type openRouterStreamChunk struct {
+	Choices []struct {
+		Delta struct {
+			Content string `json:"content"`
+		} `json:"delta"`
+	} `json:"choices"`
+}
+
+// cardExtractor incrementally scans AI-generated text for complete
+// top-level `{...}` JSON objects, tracking brace depth and
+// string state so it doesn't cut a card in half mid-stream.
+type cardExtractor struct {
+	buf      strings.Builder
+	depth    int
+	inString bool
+	escaped  bool
+	objStart int
+	scanned  int
+}
+
+// feed appends newly-arrived text and returns any JSON objects that became
+// complete as a result, regardless of their shape — callers interpret the
+// fields per card type.
+func (e *cardExtractor) feed(chunk string) []map[string]any {
+	e.buf.WriteString(chunk)
+	full := e.buf.String()
+	var completed []map[string]any
+
+	for ; e.scanned < len(full); e.scanned++ {
+		ch := full[e.scanned]
+
+		if e.escaped {
+			e.escaped = false
+			continue
+		}
+
+		switch {
+		case e.inString && ch == '\\':
+			e.escaped = true
+		case ch == '"':
+			e.inString = !e.inString
+		case !e.inString && ch == '{':
+			if e.depth == 0 {
+				e.objStart = e.scanned
+			}
+			e.depth++
+		case !e.inString && ch == '}':
+			if e.depth > 0 {
+				e.depth--
+				if e.depth == 0 {
+					candidate := full[e.objStart : e.scanned+1]
+					var raw map[string]any
+					if err := json.Unmarshal([]byte(candidate), &raw); err == nil {
+						completed = append(completed, raw)
+					}
+				}
+			}
+		}
+	}
+
+	return completed
+}
+
+// streamFlashcards returns the POST /flashcards handler. It opens a
+// streaming request to OpenRouter and re-emits each parsed card to the
+// client over SSE as soon as it arrives, inserting it into Postgres along
+// the way. If the streaming request itself can't be opened (OpenRouter
+// down, rate-limited, etc.), basic cards fall back to the provider chain,
+// using whichever backend answers first; for other card types the request
+// fails with a 500 error.
+func streamFlashcards(apiKey string, chain *providers.Chain) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		fmt.Println("\n🎯 === FLASHCARD GENERATION REQUEST (stream) ===")
+
+		type Req struct {
+			Username string          `json:"username"`
+			Topic    string          `json:"topic"`
+			Count    int             `json:"count"`
+			Level    string          `json:"level"`
+			Type     string          `json:"type"`
+			Payload  json.RawMessage `json:"payload"`
+		}
+		var body Req
+		if err := c.BodyParser(&body); err != nil || body.Username == "" || body.Topic == "" {
+			fmt.Printf("❌ Invalid flashcard request: %v\n", err)
+			return c.Status(400).JSON(fiber.Map{"error": "Invalid request"})
+		}
+		if body.Count <= 0 {
+			body.Count = 5
+		}
+		if body.Level == "" {
+			body.Level = "beginner"
+		}
+		cardType := CardType(body.Type)
+		if cardType == "" {
+			cardType = CardBasic
+		}
+
+		fmt.Printf("📝 Request details: username=%s topic=%s count=%d level=%s type=%s\n",
+			body.Username, body.Topic, body.Count, body.Level, cardType)
+
+		var userID string
+		err := db.QueryRow(context.Background(), "SELECT id FROM users WHERE username=$1", body.Username).Scan(&userID)
+		if err != nil {
+			fmt.Printf("❌ User lookup error: %v\n", err)
+			return c.Status(400).JSON(fiber.Map{"error": "User not found"})
+		}
+
+		// Image-occlusion cards come from a user-supplied image, not an AI
+		// prompt, so they skip generation entirely and insert directly.
+		if cardType == CardImage {
+			return insertImageCard(c, userID, body.Topic, body.Payload)
+		}
+
+		prompt := promptForType(cardType, body.Count, body.Topic, body.Level)
+
+		reqBody := OpenRouterRequest{
+			Model:  "deepseek/deepseek-chat-v3.1:free",
+			Stream: true,
+			Messages: []OpenRouterMsg{
+				{Role: "system", Content: systemPromptForType(cardType)},
+				{Role: "user", Content: prompt},
+			},
+		}
+		// The strict schema only describes {question, answer}, so it's only
+		// safe to hand to OpenRouter for basic cards; other types keep their
+		// own free-form prompt-described shapes.
+		if cardType == CardBasic {
+			reqBody.ResponseFormat = parser.ResponseFormatJSONSchema()
+		}
+
+		b, _ := json.Marshal(reqBody)
+		req := fasthttp.AcquireRequest()
+		req.SetRequestURI(openRouterURL)
+		req.Header.SetMethod("POST")
+		req.Header.Set("Authorization", "Bearer "+apiKey)
+		req.Header.Set("Content-Type", "application/json")
+		req.SetBody(b)
+
+		resp := fasthttp.AcquireResponse()
+		resp.StreamBody = true
+
+		client := &fasthttp.Client{}
+		fmt.Println("📡 Opening streaming AI request...")
+		doErr := client.Do(req, resp)
+		if doErr == nil && resp.StatusCode() >= 400 {
+			doErr = fmt.Errorf("status %d", resp.StatusCode())
+		}
+		if doErr != nil {
+			fasthttp.ReleaseRequest(req)
+			fasthttp.ReleaseResponse(resp)
+			if cardType != CardBasic {
+				fmt.Printf("❌ Streaming AI request failed and provider chain only supports basic cards: %v\n", doErr)
+				return c.Status(500).JSON(fiber.Map{"error": "AI request failed"})
+			}
+			fmt.Printf("⚠️ Streaming AI request failed, falling back to provider chain: %v\n", doErr)
+			return streamFromChain(c, chain, userID, body.Topic, prompt, providers.Options{
+				Topic: body.Topic, Count: body.Count, Level: body.Level,
+			})
+		}
+
+		c.Set("Content-Type", "text/event-stream")
+		c.Set("Cache-Control", "no-cache")
+		c.Set("Connection", "keep-alive")
+
+		c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+			defer fasthttp.ReleaseRequest(req)
+			defer fasthttp.ReleaseResponse(resp)
+
+			extractor := &cardExtractor{}
+			total := 0
+
+			scanner := bufio.NewScanner(resp.BodyStream())
+			for scanner.Scan() {
+				line := scanner.Text()
+				if !strings.HasPrefix(line, "data: ") {
+					continue
+				}
+				payload := strings.TrimPrefix(line, "data: ")
+				if payload == "[DONE]" {
+					break
+				}
+
+				var chunk openRouterStreamChunk
+				if err := json.Unmarshal([]byte(payload), &chunk); err != nil {
+					continue
+				}
+				for _, choice := range chunk.Choices {
+					if choice.Delta.Content == "" {
+						continue
+					}
+					for _, raw := range extractor.feed(choice.Delta.Content) {
+						question, answer, cardPayload, ok := cardFromRaw(cardType, raw)
+						if !ok {
+							fmt.Printf("⚠️ Skipping malformed %s card: %v\n", cardType, raw)
+							continue
+						}
+
+						// Basic cards are the one type with a real schema
+						// (parser.cardSchema / ResponseFormatJSONSchema), so
+						// run them through the same strict validation the
+						// provider chain uses instead of cardFromRaw's bare
+						// presence check.
+						if cardType == CardBasic {
+							valid, errs := parser.Validate([]parser.RawCard{{Question: question, Answer: answer}})
+							if len(errs) > 0 {
+								fmt.Printf("⚠️ Dropping invalid basic card: %s\n", errs[0].Reason)
+								fmt.Fprintf(w, "event: error\ndata: %s\n\n", mustJSON(fiber.Map{"validation": errs[0]}))
+								w.Flush()
+								continue
+							}
+							question, answer = valid[0].Question, valid[0].Answer
+						}
+
+						total++
+						fmt.Printf("💾 Streamed card %d: Q=%.50s...\n", total, question)
+
+						_, err := db.Exec(context.Background(),
+							"INSERT INTO flashcards (user_id, topic, question, answer, type, payload) VALUES ($1, $2, $3, $4, $5, $6)",
+							userID, body.Topic, question, answer, string(cardType), cardPayload)
+						if err != nil {
+							fmt.Printf("❌ Insert error for streamed card: %v\n", err)
+						}
+
+						frame, _ := json.Marshal(raw)
+						fmt.Fprintf(w, "event: card\ndata: %s\n\n", frame)
+						w.Flush()
+					}
+				}
+			}
+
+			if err := scanner.Err(); err != nil {
+				fmt.Printf("❌ Stream read error: %v\n", err)
+				fmt.Fprintf(w, "event: error\ndata: %s\n\n", mustJSON(fiber.Map{"error": err.Error()}))
+				w.Flush()
+				return
+			}
+
+			fmt.Printf("✅ Stream complete, %d cards generated\n", total)
+			fmt.Fprintf(w, "event: done\ndata: %s\n\n", mustJSON(fiber.Map{"count": total, "provider": "openrouter"}))
+			w.Flush()
+		})
+
+		return nil
+	}
+}
+
+// streamFromChain is the fallback path used when the primary streaming
+// request can't even be opened: it generates the whole batch through the
+// provider chain, then replays it over the same SSE protocol so the
+// frontend doesn't need to know the difference.
+func streamFromChain(c *fiber.Ctx, chain *providers.Chain, userID, topic, prompt string, opts providers.Options) error {
+	c.Set("Content-Type", "text/event-stream")
+	c.Set("Cache-Control", "no-cache")
+	c.Set("Connection", "keep-alive")
+
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		result, err := chain.Generate(context.Background(), prompt, opts)
+		if err != nil {
+			fmt.Printf("❌ Provider chain exhausted: %v\n", err)
+			fmt.Fprintf(w, "event: error\ndata: %s\n\n", mustJSON(fiber.Map{"error": err.Error()}))
+			w.Flush()
+			return
+		}
+
+		for i, card := range result.Flashcards {
+			_, err := db.Exec(context.Background(),
+				"INSERT INTO flashcards (user_id, topic, question, answer, type) VALUES ($1, $2, $3, $4, $5)",
+				userID, topic, card.Question, card.Answer, string(CardBasic))
+			if err != nil {
+				fmt.Printf("❌ Insert error for card %d: %v\n", i+1, err)
+			}
+
+			frame, _ := json.Marshal(card)
+			fmt.Fprintf(w, "event: card\ndata: %s\n\n", frame)
+			w.Flush()
+		}
+
+		fmt.Printf("✅ Chain fallback complete via %s, %d cards generated\n", result.Provider, len(result.Flashcards))
+		fmt.Fprintf(w, "event: done\ndata: %s\n\n", mustJSON(fiber.Map{"count": len(result.Flashcards), "provider": result.Provider}))
+		w.Flush()
+	})
+
+	return nil
+}
+
+func mustJSON(v any) []byte {
+	b, _ := json.Marshal(v)
+	return b
+}
+
+// insertImageCard stores a single user-supplied image-occlusion card.
+// Unlike the other types, these aren't AI-generated: the client uploads
+// the image and bounding boxes directly.
+func insertImageCard(c *fiber.Ctx, userID, topic string, rawPayload json.RawMessage) error {
+	var payload ImagePayload
+	if err := json.Unmarshal(rawPayload, &payload); err != nil || payload.ImageBase64 == "" {
+		fmt.Printf("❌ Invalid image payload: %v\n", err)
+		return c.Status(400).JSON(fiber.Map{"error": "image payload must include image_base64 and regions"})
+	}
+
+	question := fmt.Sprintf("%d occluded region(s)", len(payload.Regions))
+	var id string
+	err := db.QueryRow(context.Background(),
+		"INSERT INTO flashcards (user_id, topic, question, answer, type, payload) VALUES ($1, $2, $3, '', $4, $5) RETURNING id",
+		userID, topic, question, string(CardImage), rawPayload).Scan(&id)
+	if err != nil {
+		fmt.Printf("❌ Insert error for image card: %v\n", err)
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	fmt.Printf("✅ Inserted image card %s with %d regions\n", id, len(payload.Regions))
+	return c.JSON(fiber.Map{"id": id, "type": string(CardImage), "regions": len(payload.Regions)})
+}